@@ -4,12 +4,26 @@
 package gateway_api
 
 import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
+// controllerName is recorded as the ControllerName on every RouteParentStatus
+// this reconciler writes, so that other controllers sharing a Gateway can
+// tell which parent statuses belong to Cilium.
+const controllerName = gatewayv1beta1.GatewayController("io.cilium/gateway-controller")
+
 // httpRouteReconciler reconciles a HTTPRoute object
 type httpRouteReconciler struct {
 	client.Client
@@ -22,5 +36,155 @@ type httpRouteReconciler struct {
 func (r *httpRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&gatewayv1beta1.HTTPRoute{}).
+		Watches(&source.Kind{Type: &gatewayv1beta1.Gateway{}}, handler.EnqueueRequestsFromMapFunc(r.enqueueRoutesForGateway)).
+		Watches(&source.Kind{Type: &corev1.Service{}}, handler.EnqueueRequestsFromMapFunc(r.enqueueRoutesForService)).
+		Watches(&source.Kind{Type: &gatewayv1beta1.ReferenceGrant{}}, handler.EnqueueRequestsFromMapFunc(r.enqueueRoutesForReferenceGrant)).
 		Complete(r)
 }
+
+// Reconcile resolves backendRefs, computes per-parentRef status conditions
+// for the HTTPRoute, patches its status, and pushes the route into the
+// internal model only if at least one parent accepted it, so that invalid
+// routes never mutate xDS state.
+func (r *httpRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var route gatewayv1beta1.HTTPRoute
+	if err := r.Client.Get(ctx, req.NamespacedName, &route); err != nil {
+		if k8serrors.IsNotFound(err) {
+			r.Model.Delete(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("getting httproute %s: %w", req.NamespacedName, err)
+	}
+
+	parentStatuses, anyAccepted := r.computeParentStatuses(ctx, &route)
+
+	if err := r.patchStatus(ctx, &route, parentStatuses); err != nil {
+		return ctrl.Result{}, fmt.Errorf("updating status for httproute %s: %w", req.NamespacedName, err)
+	}
+
+	if anyAccepted {
+		r.Model.Upsert(&route)
+	} else {
+		r.Model.Delete(req.NamespacedName)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// patchStatus writes parentStatuses to route.Status.Parents, skipping the
+// API call entirely when nothing changed.
+func (r *httpRouteReconciler) patchStatus(ctx context.Context, route *gatewayv1beta1.HTTPRoute, parentStatuses []gatewayv1beta1.RouteParentStatus) error {
+	if routeParentStatusesEqual(route.Status.Parents, parentStatuses) {
+		return nil
+	}
+
+	original := route.DeepCopy()
+	route.Status.Parents = parentStatuses
+	return r.Client.Status().Patch(ctx, route, client.MergeFrom(original))
+}
+
+// enqueueRoutesForGateway enqueues every HTTPRoute that references the
+// Gateway obj via a parentRef, so that attachment status is recomputed when
+// the Gateway changes.
+func (r *httpRouteReconciler) enqueueRoutesForGateway(obj client.Object) []reconcile.Request {
+	gw, ok := obj.(*gatewayv1beta1.Gateway)
+	if !ok {
+		return nil
+	}
+
+	var routes gatewayv1beta1.HTTPRouteList
+	if err := r.Client.List(context.Background(), &routes); err != nil {
+		return nil
+	}
+
+	var reqs []reconcile.Request
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		for _, parentRef := range route.Spec.ParentRefs {
+			ns := route.Namespace
+			if parentRef.Namespace != nil {
+				ns = string(*parentRef.Namespace)
+			}
+			if ns == gw.Namespace && string(parentRef.Name) == gw.Name {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: route.Namespace, Name: route.Name}})
+				break
+			}
+		}
+	}
+	return reqs
+}
+
+// enqueueRoutesForService enqueues every HTTPRoute whose backendRefs
+// reference obj, so that ResolvedRefs is recomputed when the Service
+// appears, disappears, or is otherwise changed.
+func (r *httpRouteReconciler) enqueueRoutesForService(obj client.Object) []reconcile.Request {
+	svc, ok := obj.(*corev1.Service)
+	if !ok {
+		return nil
+	}
+
+	var routes gatewayv1beta1.HTTPRouteList
+	if err := r.Client.List(context.Background(), &routes); err != nil {
+		return nil
+	}
+
+	var reqs []reconcile.Request
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		for _, rule := range route.Spec.Rules {
+			for _, backend := range rule.BackendRefs {
+				ns := route.Namespace
+				if backend.Namespace != nil {
+					ns = string(*backend.Namespace)
+				}
+				if ns == svc.Namespace && string(backend.Name) == svc.Name {
+					reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: route.Namespace, Name: route.Name}})
+				}
+			}
+		}
+	}
+	return reqs
+}
+
+// enqueueRoutesForReferenceGrant enqueues every HTTPRoute with a
+// cross-namespace parentRef or backendRef pointing at the namespace the
+// changed ReferenceGrant lives in, since its permission may have just been
+// granted or revoked.
+func (r *httpRouteReconciler) enqueueRoutesForReferenceGrant(obj client.Object) []reconcile.Request {
+	grant, ok := obj.(*gatewayv1beta1.ReferenceGrant)
+	if !ok {
+		return nil
+	}
+
+	var routes gatewayv1beta1.HTTPRouteList
+	if err := r.Client.List(context.Background(), &routes); err != nil {
+		return nil
+	}
+
+	var reqs []reconcile.Request
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if routeReferencesNamespace(route, grant.Namespace) {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: route.Namespace, Name: route.Name}})
+		}
+	}
+	return reqs
+}
+
+// routeReferencesNamespace reports whether route has any parentRef or
+// backendRef explicitly targeting namespace.
+func routeReferencesNamespace(route *gatewayv1beta1.HTTPRoute, namespace string) bool {
+	for _, parentRef := range route.Spec.ParentRefs {
+		if parentRef.Namespace != nil && string(*parentRef.Namespace) == namespace {
+			return true
+		}
+	}
+	for _, rule := range route.Spec.Rules {
+		for _, backend := range rule.BackendRefs {
+			if backend.Namespace != nil && string(*backend.Namespace) == namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
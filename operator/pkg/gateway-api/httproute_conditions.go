@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package gateway_api
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// errRefNotPermitted is returned by resolveBackendRef when a cross-namespace
+// backendRef is not allowed by any ReferenceGrant in the target namespace.
+type errRefNotPermitted struct {
+	target string
+}
+
+func (e errRefNotPermitted) Error() string {
+	return fmt.Sprintf("reference to %s is not permitted by any ReferenceGrant", e.target)
+}
+
+// errBackendNotFound is returned by resolveBackendRef when the referenced
+// backend object does not exist.
+type errBackendNotFound struct {
+	target string
+}
+
+func (e errBackendNotFound) Error() string {
+	return fmt.Sprintf("backend %s not found", e.target)
+}
+
+// computeParentStatuses resolves route's backendRefs and evaluates each
+// parentRef's attachment to its Gateway, returning a RouteParentStatus per
+// parentRef plus whether at least one parent accepted the route.
+func (r *httpRouteReconciler) computeParentStatuses(ctx context.Context, route *gatewayv1beta1.HTTPRoute) ([]gatewayv1beta1.RouteParentStatus, bool) {
+	resolveErr := r.resolveBackendRefs(ctx, route)
+
+	statuses := make([]gatewayv1beta1.RouteParentStatus, 0, len(route.Spec.ParentRefs))
+	anyAccepted := false
+
+	for _, parentRef := range route.Spec.ParentRefs {
+		accepted, acceptedReason, acceptedMessage := r.checkGatewayAcceptance(ctx, route, parentRef)
+
+		resolvedRefs := resolveErr == nil
+		resolvedReason, resolvedMessage := string(gatewayv1beta1.RouteReasonResolvedRefs), "All backend references are resolved"
+		if resolveErr != nil {
+			resolvedReason, resolvedMessage = reasonForResolveError(resolveErr)
+		}
+
+		if accepted && !resolvedRefs {
+			// The route attached successfully but not every rule could be
+			// programmed, so mark it partially invalid rather than fully
+			// rejecting it.
+			acceptedReason = string(gatewayv1beta1.RouteReasonPartiallyInvalid)
+			acceptedMessage = resolvedMessage
+		}
+		if accepted {
+			anyAccepted = true
+		}
+
+		statuses = append(statuses, gatewayv1beta1.RouteParentStatus{
+			ParentRef:      parentRef,
+			ControllerName: controllerName,
+			Conditions: []metav1.Condition{
+				{
+					Type:               string(gatewayv1beta1.RouteConditionAccepted),
+					Status:             toConditionStatus(accepted),
+					ObservedGeneration: route.Generation,
+					Reason:             acceptedReason,
+					Message:            acceptedMessage,
+				},
+				{
+					Type:               string(gatewayv1beta1.RouteConditionResolvedRefs),
+					Status:             toConditionStatus(resolvedRefs),
+					ObservedGeneration: route.Generation,
+					Reason:             resolvedReason,
+					Message:            resolvedMessage,
+				},
+			},
+		})
+	}
+
+	return statuses, anyAccepted
+}
+
+// checkGatewayAcceptance reports whether the Gateway referenced by parentRef
+// exists and, for cross-namespace attachment, has a listener that allows
+// routes from route's namespace.
+func (r *httpRouteReconciler) checkGatewayAcceptance(ctx context.Context, route *gatewayv1beta1.HTTPRoute, parentRef gatewayv1beta1.ParentReference) (bool, string, string) {
+	if parentRef.Kind != nil && string(*parentRef.Kind) != "Gateway" {
+		return false, string(gatewayv1beta1.RouteReasonNoMatchingParent), fmt.Sprintf("unsupported parentRef kind %q", *parentRef.Kind)
+	}
+
+	gwNamespace := route.Namespace
+	if parentRef.Namespace != nil {
+		gwNamespace = string(*parentRef.Namespace)
+	}
+
+	var gw gatewayv1beta1.Gateway
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: gwNamespace, Name: string(parentRef.Name)}, &gw); err != nil {
+		return false, string(gatewayv1beta1.RouteReasonNoMatchingParent), "referenced Gateway does not exist"
+	}
+
+	if gwNamespace != route.Namespace {
+		allowed, err := r.gatewayAllowsCrossNamespaceRoutes(ctx, &gw, route.Namespace)
+		if err != nil {
+			return false, string(gatewayv1beta1.RouteReasonNoMatchingParent), fmt.Sprintf("checking namespace selector: %s", err)
+		}
+		if !allowed {
+			return false, string(gatewayv1beta1.RouteReasonNoMatchingParent), "Gateway does not allow attachment from this namespace"
+		}
+	}
+
+	return true, string(gatewayv1beta1.RouteReasonAccepted), "Accepted by Gateway"
+}
+
+// gatewayAllowsCrossNamespaceRoutes reports whether gw has at least one
+// listener whose AllowedRoutes permits routes from routeNamespace: either
+// because it allows all namespaces, or because routeNamespace's labels match
+// the listener's namespace selector.
+func (r *httpRouteReconciler) gatewayAllowsCrossNamespaceRoutes(ctx context.Context, gw *gatewayv1beta1.Gateway, routeNamespace string) (bool, error) {
+	var ns corev1.Namespace
+	nsFetched := false
+
+	for _, listener := range gw.Spec.Listeners {
+		if listener.AllowedRoutes == nil || listener.AllowedRoutes.Namespaces == nil || listener.AllowedRoutes.Namespaces.From == nil {
+			continue
+		}
+		switch *listener.AllowedRoutes.Namespaces.From {
+		case gatewayv1beta1.NamespacesFromAll:
+			return true, nil
+		case gatewayv1beta1.NamespacesFromSelector:
+			if !nsFetched {
+				if err := r.Client.Get(ctx, types.NamespacedName{Name: routeNamespace}, &ns); err != nil {
+					return false, fmt.Errorf("getting namespace %q: %w", routeNamespace, err)
+				}
+				nsFetched = true
+			}
+			selector, err := metav1.LabelSelectorAsSelector(listener.AllowedRoutes.Namespaces.Selector)
+			if err != nil {
+				return false, fmt.Errorf("parsing namespace selector: %w", err)
+			}
+			if selector.Matches(labels.Set(ns.Labels)) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// resolveBackendRefs resolves every backendRef across every rule of route,
+// returning the first error encountered.
+func (r *httpRouteReconciler) resolveBackendRefs(ctx context.Context, route *gatewayv1beta1.HTTPRoute) error {
+	for _, rule := range route.Spec.Rules {
+		for _, backend := range rule.BackendRefs {
+			if err := r.resolveBackendRef(ctx, route, backend.BackendRef); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolveBackendRef checks that ref is permitted by a ReferenceGrant when it
+// crosses namespaces, and that the referenced object exists.
+func (r *httpRouteReconciler) resolveBackendRef(ctx context.Context, route *gatewayv1beta1.HTTPRoute, ref gatewayv1beta1.BackendRef) error {
+	group := ""
+	if ref.Group != nil {
+		group = string(*ref.Group)
+	}
+	kind := "Service"
+	if ref.Kind != nil {
+		kind = string(*ref.Kind)
+	}
+
+	targetNamespace := route.Namespace
+	if ref.Namespace != nil {
+		targetNamespace = string(*ref.Namespace)
+	}
+
+	target := fmt.Sprintf("%s/%s/%s", kind, targetNamespace, ref.Name)
+
+	if targetNamespace != route.Namespace {
+		allowed, err := r.isReferenceAllowed(ctx, gatewayv1beta1.GroupName, "HTTPRoute", route.Namespace, group, kind, targetNamespace, string(ref.Name))
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errRefNotPermitted{target: target}
+		}
+	}
+
+	if kind != "Service" {
+		// Cilium only resolves plain Service backends; other kinds (e.g.
+		// ServiceImport) are passed through unresolved.
+		return nil
+	}
+
+	var svc corev1.Service
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: targetNamespace, Name: string(ref.Name)}, &svc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return errBackendNotFound{target: target}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// reasonForResolveError maps a resolveBackendRef error to the
+// ResolvedRefs condition reason and message to surface on the route status.
+func reasonForResolveError(err error) (string, string) {
+	switch e := err.(type) {
+	case errRefNotPermitted:
+		return string(gatewayv1beta1.RouteReasonRefNotPermitted), e.Error()
+	case errBackendNotFound:
+		return string(gatewayv1beta1.RouteReasonBackendNotFound), e.Error()
+	default:
+		return string(gatewayv1beta1.RouteReasonBackendNotFound), err.Error()
+	}
+}
+
+func toConditionStatus(ok bool) metav1.ConditionStatus {
+	if ok {
+		return metav1.ConditionTrue
+	}
+	return metav1.ConditionFalse
+}
+
+// routeParentStatusesEqual reports whether a and b carry the same parent
+// statuses, ignoring LastTransitionTime so that a no-op reconcile does not
+// produce a status patch.
+func routeParentStatusesEqual(a, b []gatewayv1beta1.RouteParentStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !reflect.DeepEqual(a[i].ParentRef, b[i].ParentRef) || a[i].ControllerName != b[i].ControllerName {
+			return false
+		}
+		if len(a[i].Conditions) != len(b[i].Conditions) {
+			return false
+		}
+		for j := range a[i].Conditions {
+			ca, cb := a[i].Conditions[j], b[i].Conditions[j]
+			if ca.Type != cb.Type || ca.Status != cb.Status || ca.Reason != cb.Reason || ca.Message != cb.Message || ca.ObservedGeneration != cb.ObservedGeneration {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package gateway_api
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func fromNamespaces(from gatewayv1beta1.FromNamespaces, selector *metav1.LabelSelector) *gatewayv1beta1.RouteNamespaces {
+	return &gatewayv1beta1.RouteNamespaces{From: &from, Selector: selector}
+}
+
+func gatewayWithAllowedRoutes(namespaces *gatewayv1beta1.RouteNamespaces) *gatewayv1beta1.Gateway {
+	return &gatewayv1beta1.Gateway{
+		Spec: gatewayv1beta1.GatewaySpec{
+			Listeners: []gatewayv1beta1.Listener{{
+				AllowedRoutes: &gatewayv1beta1.AllowedRoutes{Namespaces: namespaces},
+			}},
+		},
+	}
+}
+
+func TestGatewayAllowsCrossNamespaceRoutes(t *testing.T) {
+	prodSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+
+	prodNS := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}}}
+	devNS := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev", Labels: map[string]string{"env": "dev"}}}
+
+	tests := []struct {
+		name    string
+		gw      *gatewayv1beta1.Gateway
+		routeNS *corev1.Namespace
+		want    bool
+	}{
+		{
+			name:    "NamespacesFromAll permits any namespace",
+			gw:      gatewayWithAllowedRoutes(fromNamespaces(gatewayv1beta1.NamespacesFromAll, nil)),
+			routeNS: devNS,
+			want:    true,
+		},
+		{
+			name:    "NamespacesFromSame denies a different namespace",
+			gw:      gatewayWithAllowedRoutes(fromNamespaces(gatewayv1beta1.NamespacesFromSame, nil)),
+			routeNS: devNS,
+			want:    false,
+		},
+		{
+			name:    "NamespacesFromSelector permits a namespace matching the selector",
+			gw:      gatewayWithAllowedRoutes(fromNamespaces(gatewayv1beta1.NamespacesFromSelector, prodSelector)),
+			routeNS: prodNS,
+			want:    true,
+		},
+		{
+			name:    "NamespacesFromSelector denies a namespace that does not match the selector",
+			gw:      gatewayWithAllowedRoutes(fromNamespaces(gatewayv1beta1.NamespacesFromSelector, prodSelector)),
+			routeNS: devNS,
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(tt.routeNS).Build()
+			r := &httpRouteReconciler{Client: client}
+
+			got, err := r.gatewayAllowsCrossNamespaceRoutes(context.Background(), tt.gw, tt.routeNS.Name)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveBackendRefSameNamespaceResolvesExistingService(t *testing.T) {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "checkout"}}
+	client := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(svc).Build()
+	r := &httpRouteReconciler{Client: client}
+
+	route := &gatewayv1beta1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "route"}}
+	ref := gatewayv1beta1.BackendRef{BackendObjectReference: gatewayv1beta1.BackendObjectReference{Name: "checkout"}}
+
+	if err := r.resolveBackendRef(context.Background(), route, ref); err != nil {
+		t.Fatalf("expected no error resolving an existing same-namespace Service, got: %v", err)
+	}
+}
+
+func TestResolveBackendRefMissingServiceIsBackendNotFound(t *testing.T) {
+	client := fake.NewClientBuilder().WithScheme(newTestScheme(t)).Build()
+	r := &httpRouteReconciler{Client: client}
+
+	route := &gatewayv1beta1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "route"}}
+	ref := gatewayv1beta1.BackendRef{BackendObjectReference: gatewayv1beta1.BackendObjectReference{Name: "missing"}}
+
+	err := r.resolveBackendRef(context.Background(), route, ref)
+	if _, ok := err.(errBackendNotFound); !ok {
+		t.Fatalf("expected errBackendNotFound, got: %v", err)
+	}
+}
+
+func TestResolveBackendRefCrossNamespaceWithoutGrantIsRefNotPermitted(t *testing.T) {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "checkout"}}
+	client := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(svc).Build()
+	r := &httpRouteReconciler{Client: client}
+
+	route := &gatewayv1beta1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "route"}}
+	namespace := gatewayv1beta1.Namespace("team-b")
+	ref := gatewayv1beta1.BackendRef{BackendObjectReference: gatewayv1beta1.BackendObjectReference{
+		Name:      "checkout",
+		Namespace: &namespace,
+	}}
+
+	err := r.resolveBackendRef(context.Background(), route, ref)
+	if _, ok := err.(errRefNotPermitted); !ok {
+		t.Fatalf("expected errRefNotPermitted, got: %v", err)
+	}
+}
+
+func TestResolveBackendRefCrossNamespaceWithGrantResolves(t *testing.T) {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "checkout"}}
+	grant := &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "allow-team-a"},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{
+				{Group: gatewayv1beta1.GroupName, Kind: "HTTPRoute", Namespace: "team-a"},
+			},
+			To: []gatewayv1beta1.ReferenceGrantTo{
+				{Kind: "Service", Name: strPtr("checkout")},
+			},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(svc, grant).Build()
+	r := &httpRouteReconciler{Client: client}
+
+	route := &gatewayv1beta1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "route"}}
+	namespace := gatewayv1beta1.Namespace("team-b")
+	ref := gatewayv1beta1.BackendRef{BackendObjectReference: gatewayv1beta1.BackendObjectReference{
+		Name:      "checkout",
+		Namespace: &namespace,
+	}}
+
+	if err := r.resolveBackendRef(context.Background(), route, ref); err != nil {
+		t.Fatalf("expected the ReferenceGrant to permit this reference, got: %v", err)
+	}
+}
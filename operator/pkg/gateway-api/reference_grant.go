@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package gateway_api
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// isReferenceAllowed reports whether some ReferenceGrant in toNamespace
+// permits an object of kind fromGroup/fromKind in fromNamespace to reference
+// an object of kind toGroup/toKind (optionally named toName) in toNamespace.
+// References within the same namespace are always allowed.
+func (r *httpRouteReconciler) isReferenceAllowed(ctx context.Context, fromGroup, fromKind, fromNamespace, toGroup, toKind, toNamespace, toName string) (bool, error) {
+	if fromNamespace == toNamespace {
+		return true, nil
+	}
+
+	var grants gatewayv1beta1.ReferenceGrantList
+	if err := r.Client.List(ctx, &grants, client.InNamespace(toNamespace)); err != nil {
+		return false, fmt.Errorf("listing reference grants in namespace %q: %w", toNamespace, err)
+	}
+
+	for _, grant := range grants.Items {
+		if referenceGrantPermits(&grant, fromGroup, fromKind, fromNamespace, toGroup, toKind, toName) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// referenceGrantPermits reports whether grant's From/To entries cover the
+// given reference.
+func referenceGrantPermits(grant *gatewayv1beta1.ReferenceGrant, fromGroup, fromKind, fromNamespace, toGroup, toKind, toName string) bool {
+	matchesFrom := false
+	for _, from := range grant.Spec.From {
+		if string(from.Group) == fromGroup && string(from.Kind) == fromKind && string(from.Namespace) == fromNamespace {
+			matchesFrom = true
+			break
+		}
+	}
+	if !matchesFrom {
+		return false
+	}
+
+	for _, to := range grant.Spec.To {
+		if string(to.Group) != toGroup || string(to.Kind) != toKind {
+			continue
+		}
+		if to.Name == nil || string(*to.Name) == toName {
+			return true
+		}
+	}
+
+	return false
+}
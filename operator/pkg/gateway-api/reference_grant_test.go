@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package gateway_api
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding client-go scheme: %v", err)
+	}
+	if err := gatewayv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding gateway-api scheme: %v", err)
+	}
+	return scheme
+}
+
+func strPtr(s gatewayv1beta1.ObjectName) *gatewayv1beta1.ObjectName { return &s }
+
+func TestReferenceGrantPermits(t *testing.T) {
+	grant := &gatewayv1beta1.ReferenceGrant{
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{
+				{Group: gatewayv1beta1.GroupName, Kind: "HTTPRoute", Namespace: "team-a"},
+			},
+			To: []gatewayv1beta1.ReferenceGrantTo{
+				{Kind: "Service", Name: strPtr("checkout")},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                                string
+		fromGroup, fromKind, fromNamespace  string
+		toGroup, toKind, toNamespace, toName string
+		want                                bool
+	}{
+		{"matches from and named to", string(gatewayv1beta1.GroupName), "HTTPRoute", "team-a", "", "Service", "team-b", "checkout", true},
+		{"wrong from namespace", string(gatewayv1beta1.GroupName), "HTTPRoute", "team-c", "", "Service", "team-b", "checkout", false},
+		{"wrong to name", string(gatewayv1beta1.GroupName), "HTTPRoute", "team-a", "", "Service", "team-b", "other", false},
+		{"wrong to kind", string(gatewayv1beta1.GroupName), "HTTPRoute", "team-a", "", "ServiceImport", "team-b", "checkout", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := referenceGrantPermits(grant, tt.fromGroup, tt.fromKind, tt.fromNamespace, tt.toGroup, tt.toKind, tt.toName)
+			if got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReferenceGrantPermitsUnnamedTo(t *testing.T) {
+	grant := &gatewayv1beta1.ReferenceGrant{
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{
+				{Group: gatewayv1beta1.GroupName, Kind: "HTTPRoute", Namespace: "team-a"},
+			},
+			To: []gatewayv1beta1.ReferenceGrantTo{
+				{Kind: "Service"},
+			},
+		},
+	}
+
+	if !referenceGrantPermits(grant, string(gatewayv1beta1.GroupName), "HTTPRoute", "team-a", "", "Service", "anything") {
+		t.Fatal("expected an unnamed To entry to permit any Service name")
+	}
+}
+
+func TestIsReferenceAllowedSameNamespaceAlwaysAllowed(t *testing.T) {
+	client := fake.NewClientBuilder().WithScheme(newTestScheme(t)).Build()
+	r := &httpRouteReconciler{Client: client}
+
+	allowed, err := r.isReferenceAllowed(context.Background(), string(gatewayv1beta1.GroupName), "HTTPRoute", "team-a", "", "Service", "team-a", "checkout")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !allowed {
+		t.Fatal("expected same-namespace references to always be allowed")
+	}
+}
+
+func TestIsReferenceAllowedCrossNamespace(t *testing.T) {
+	grant := &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "allow-team-a"},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{
+				{Group: gatewayv1beta1.GroupName, Kind: "HTTPRoute", Namespace: "team-a"},
+			},
+			To: []gatewayv1beta1.ReferenceGrantTo{
+				{Kind: "Service", Name: strPtr("checkout")},
+			},
+		},
+	}
+
+	client := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(grant).Build()
+	r := &httpRouteReconciler{Client: client}
+
+	allowed, err := r.isReferenceAllowed(context.Background(), string(gatewayv1beta1.GroupName), "HTTPRoute", "team-a", "", "Service", "team-b", "checkout")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !allowed {
+		t.Fatal("expected the ReferenceGrant to permit this cross-namespace reference")
+	}
+
+	denied, err := r.isReferenceAllowed(context.Background(), string(gatewayv1beta1.GroupName), "HTTPRoute", "team-a", "", "Service", "team-b", "other-service")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if denied {
+		t.Fatal("expected references to a service not covered by the grant to be denied")
+	}
+}
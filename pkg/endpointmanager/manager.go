@@ -18,7 +18,9 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cilium/cilium/pkg/completion"
@@ -43,16 +45,57 @@ var (
 	metricsOnce sync.Once
 )
 
+// numShards is the number of shards the endpoints and endpointsAux maps are
+// split into. Every shard carries its own lock, so lookups and updates that
+// land on different shards no longer contend with each other. 64 shards is
+// enough to keep per-shard map sizes small even on nodes running several
+// thousand endpoints, while keeping the per-shard lock overhead negligible.
+const numShards = 64
+
+// idShard holds a partition of the endpoints map, keyed by endpoint ID.
+type idShard struct {
+	mutex lock.RWMutex
+	byID  map[uint16]*endpoint.Endpoint
+}
+
+// auxShard holds a partition of the endpointsAux map, keyed by the
+// endpointid-prefixed string identifiers (container ID, pod name, IP, etc).
+type auxShard struct {
+	mutex lock.RWMutex
+	byKey map[string]*endpoint.Endpoint
+}
+
 // EndpointManager is a structure designed for containing state about the
 // collection of locally running endpoints.
 type EndpointManager struct {
-	// mutex protects endpoints and endpointsAux
-	mutex lock.RWMutex
-
-	// endpoints is the global list of endpoints indexed by ID. mutex must
-	// be held to read and write.
-	endpoints    map[uint16]*endpoint.Endpoint
-	endpointsAux map[string]*endpoint.Endpoint
+	// snapshotVersion must be the first field of the struct so that it stays
+	// 64-bit aligned for atomic access on 32-bit platforms. It is bumped by
+	// every UpdateIDReference/RemoveID/RemoveAll call. Writers only ever
+	// increment this counter (an O(1) operation); GetEndpoints rebuilds the
+	// cached snapshot lazily, only when it notices the version has moved on.
+	snapshotVersion uint64
+
+	// idShards partitions the global list of endpoints, indexed by ID, into
+	// numShards independently-locked buckets.
+	idShards [numShards]*idShard
+
+	// auxShards partitions the secondary endpointsAux index, indexed by
+	// container ID, pod name, IP, etc, into numShards independently-locked
+	// buckets.
+	auxShards [numShards]*auxShard
+
+	// snapshot holds an immutable *endpointSnapshot built from idShards,
+	// swapped in by GetEndpoints whenever it is stale. GetEndpoints and
+	// GetPolicyEndpoints read from it directly, so long-running iteration
+	// over the endpoint list never blocks concurrent Lookup*/Update*/
+	// Remove* calls, and those calls never pay the cost of rebuilding it.
+	snapshot atomic.Value
+
+	// snapshotMutex serializes snapshot rebuilds, so that concurrent
+	// GetEndpoints callers racing on a stale snapshot don't duplicate the
+	// scan, and a rebuild that started earlier can never clobber one that
+	// started later with an older result.
+	snapshotMutex lock.Mutex
 
 	// EndpointSynchronizer updates external resources (e.g., Kubernetes) with
 	// up-to-date information about endpoints managed by the endpoint manager.
@@ -62,11 +105,25 @@ type EndpointManager struct {
 	// of an endpoint.
 	checker EndpointChecker
 
-	// A mark-and-sweep garbage collector may operate on the endpoint list.
-	// This is configured via WithPeriodicEndpointGC() and will mark
-	// endpoints for removal on one run of the controller, then in the
-	// subsequent controller run will remove the endpoints.
-	markedEndpoints []uint16
+	// gcPolicy controls the hysteresis and responsiveness of the endpoint
+	// garbage collector, configured via WithPeriodicEndpointGC().
+	gcPolicy GCPolicy
+
+	// gcMutex protects gcFailures.
+	gcMutex lock.Mutex
+
+	// gcFailures counts consecutive failed Checker.Check calls per endpoint
+	// ID. An endpoint is only swept once its count reaches
+	// gcPolicy.MinMarks, and the count is cleared as soon as a Check call
+	// succeeds.
+	gcFailures map[uint16]int
+
+	// subscribersMutex protects subscribers.
+	subscribersMutex lock.Mutex
+
+	// subscribers holds the set of channels returned by Subscribe() that
+	// are still active.
+	subscribers map[chan EndpointEvent]struct{}
 }
 
 // EndpointResourceSynchronizer is an interface which synchronizes CiliumEndpoint
@@ -77,13 +134,54 @@ type EndpointResourceSynchronizer interface {
 
 // NewEndpointManager creates a new EndpointManager.
 func NewEndpointManager(epSynchronizer EndpointResourceSynchronizer) *EndpointManager {
-	mgr := EndpointManager{
-		endpoints:                    make(map[uint16]*endpoint.Endpoint),
-		endpointsAux:                 make(map[string]*endpoint.Endpoint),
+	mgr := &EndpointManager{
 		EndpointResourceSynchronizer: epSynchronizer,
+		subscribers:                  make(map[chan EndpointEvent]struct{}),
 	}
+	for i := range mgr.idShards {
+		mgr.idShards[i] = &idShard{byID: make(map[uint16]*endpoint.Endpoint)}
+	}
+	for i := range mgr.auxShards {
+		mgr.auxShards[i] = &auxShard{byKey: make(map[string]*endpoint.Endpoint)}
+	}
+	mgr.snapshot.Store(&endpointSnapshot{eps: make([]*endpoint.Endpoint, 0)})
+
+	return mgr
+}
+
+// endpointSnapshot pairs a point-in-time endpoint list with the
+// snapshotVersion it was built from, so GetEndpoints can tell whether a
+// cached copy is still current without re-scanning the shards.
+type endpointSnapshot struct {
+	version uint64
+	eps     []*endpoint.Endpoint
+}
+
+// fnv32 computes the 32-bit FNV-1a hash of data. It is used to pick a shard
+// for a given key without allocating a hash.Hash.
+func fnv32(data []byte) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	hash := uint32(offset32)
+	for _, b := range data {
+		hash ^= uint32(b)
+		hash *= prime32
+	}
+	return hash
+}
+
+// idShardFor returns the idShard responsible for the given endpoint ID.
+func (mgr *EndpointManager) idShardFor(id uint16) *idShard {
+	h := fnv32([]byte{byte(id), byte(id >> 8)})
+	return mgr.idShards[h%numShards]
+}
 
-	return &mgr
+// auxShardFor returns the auxShard responsible for the given endpointsAux key.
+func (mgr *EndpointManager) auxShardFor(key string) *auxShard {
+	h := fnv32([]byte(key))
+	return mgr.auxShards[h%numShards]
 }
 
 // EndpointChecker can verify whether an endpoint is currently healthy.
@@ -92,10 +190,52 @@ type EndpointChecker interface {
 	DeleteEndpoint(*endpoint.Endpoint) int
 }
 
+// GCPolicy configures the hysteresis and responsiveness of the endpoint
+// garbage collector run by WithPeriodicEndpointGC.
+type GCPolicy struct {
+	// MinMarks is the number of consecutive failed Checker.Check calls an
+	// endpoint must accumulate before it is swept. Defaults to 2 if <= 0.
+	MinMarks int
+
+	// MaxMarks caps the failure counter tracked per endpoint, so that an
+	// endpoint which has been failing for a long time does not grow it
+	// without bound. Defaults to MinMarks if <= 0.
+	MaxMarks int
+
+	// RequireConsecutive, if true, resets an endpoint's failure counter to
+	// zero as soon as one Checker.Check call succeeds. If false, a
+	// successful check only decrements the counter by one, so an endpoint
+	// that mostly fails but occasionally passes is still eventually swept.
+	RequireConsecutive bool
+
+	// ImmediateSweepOnEvent, if true, makes NotifyStale perform a sweep of
+	// the notified endpoint immediately instead of waiting for the next
+	// periodic run of the controller.
+	ImmediateSweepOnEvent bool
+}
+
+// withDefaults returns a copy of p with zero-valued fields replaced by their
+// defaults.
+func (p GCPolicy) withDefaults() GCPolicy {
+	if p.MinMarks <= 0 {
+		p.MinMarks = 2
+	}
+	if p.MaxMarks <= 0 {
+		p.MaxMarks = p.MinMarks
+	}
+	return p
+}
+
 // WithPeriodicEndpointGC runs a controller to periodically garbage collect
-// endpoints that match the specified checker.
-func (mgr *EndpointManager) WithPeriodicEndpointGC(ctx context.Context, checker EndpointChecker, interval time.Duration) *EndpointManager {
+// endpoints that match the specified checker, according to policy.
+func (mgr *EndpointManager) WithPeriodicEndpointGC(ctx context.Context, checker EndpointChecker, interval time.Duration, policy GCPolicy) *EndpointManager {
 	mgr.checker = checker
+
+	mgr.gcMutex.Lock()
+	mgr.gcPolicy = policy.withDefaults()
+	mgr.gcFailures = make(map[uint16]int)
+	mgr.gcMutex.Unlock()
+
 	controller.NewManager().UpdateController("endpoint-gc",
 		controller.ControllerParams{
 			DoFunc:      mgr.markAndSweep,
@@ -105,22 +245,11 @@ func (mgr *EndpointManager) WithPeriodicEndpointGC(ctx context.Context, checker
 	return mgr
 }
 
-// markAndSweep performs a two-phase garbage collection of endpoints using the
-// configured EndpointChecker.
-//
-// 1) Mark all endpoints that require GC. Do not GC these endpoints this round.
-// 2) Sweep all endpoints marked as requiring GC during the previous iteration.
-//
-// This way, if there is a temporary condition that will be resolved by other
-// components in the system, then we will not flag warnings about the system
-// getting out-of-sync.
+// markAndSweep marks every endpoint that currently fails the configured
+// EndpointChecker and immediately sweeps any endpoint whose consecutive
+// failure count has reached the configured hysteresis threshold.
 func (mgr *EndpointManager) markAndSweep(ctx context.Context) error {
-	marked := mgr.markEndpoints()
-
-	mgr.mutex.Lock()
-	toSweep := mgr.markedEndpoints
-	mgr.markedEndpoints = marked
-	mgr.mutex.Unlock()
+	toSweep := mgr.markEndpoints(mgr.GetEndpoints())
 
 	// Avoid returning an error which would cause the calling controller to
 	// re-run the garbage collection more frequently than the RunInterval.
@@ -128,23 +257,91 @@ func (mgr *EndpointManager) markAndSweep(ctx context.Context) error {
 	return nil
 }
 
-// markEndpoints runs all endpoints in the manager against the configured
-// EndpointChecker and returns a slice of endpoint ids that require garbage
-// collection.
-func (mgr *EndpointManager) markEndpoints() []uint16 {
-	mgr.mutex.RLock()
-	defer mgr.mutex.RUnlock()
+// markEndpoints runs eps against the configured EndpointChecker, updating
+// each endpoint's consecutive-failure counter, and returns the ids of the
+// endpoints whose counter has reached gcPolicy.MinMarks and so are due for
+// sweeping. An endpoint whose counter already stands at gcPolicy.MinMarks
+// (as seeded by NotifyStale) is returned without consulting the checker.
+func (mgr *EndpointManager) markEndpoints(eps []*endpoint.Endpoint) []uint16 {
+	mgr.gcMutex.Lock()
+	defer mgr.gcMutex.Unlock()
+
+	seen := make(map[uint16]struct{}, len(eps))
+	needsGC := make([]uint16, 0, len(eps))
 
 	// TODO: Consider exposing visibility via Endpoint.SetState().
-	needsGC := make([]uint16, 0, len(mgr.endpoints))
-	for eid, ep := range mgr.endpoints {
+	for _, ep := range eps {
+		seen[ep.ID] = struct{}{}
+
+		if mgr.gcFailures[ep.ID] >= mgr.gcPolicy.MinMarks {
+			needsGC = append(needsGC, ep.ID)
+			delete(mgr.gcFailures, ep.ID)
+			continue
+		}
+
 		if err := mgr.checker.Check(ep); err != nil {
-			needsGC = append(needsGC, eid)
+			count := mgr.gcFailures[ep.ID] + 1
+			if count > mgr.gcPolicy.MaxMarks {
+				count = mgr.gcPolicy.MaxMarks
+			}
+			mgr.gcFailures[ep.ID] = count
+			metrics.EndpointGCMarked.Inc()
+
+			if count >= mgr.gcPolicy.MinMarks {
+				needsGC = append(needsGC, ep.ID)
+				delete(mgr.gcFailures, ep.ID)
+			}
+			continue
+		}
+
+		if count, marked := mgr.gcFailures[ep.ID]; marked {
+			if mgr.gcPolicy.RequireConsecutive || count <= 1 {
+				delete(mgr.gcFailures, ep.ID)
+			} else {
+				mgr.gcFailures[ep.ID] = count - 1
+			}
+			metrics.EndpointGCUnmarked.Inc()
 		}
 	}
+
+	// Forget about endpoints that no longer exist so gcFailures does not
+	// grow without bound.
+	for id := range mgr.gcFailures {
+		if _, ok := seen[id]; !ok {
+			delete(mgr.gcFailures, id)
+		}
+	}
+
 	return needsGC
 }
 
+// NotifyStale marks endpoint id as due for garbage collection immediately,
+// bypassing the hysteresis counter. Callers such as the CNI DEL handler or a
+// netlink NETNS-delete watcher use this to reap an endpoint as soon as they
+// observe it is gone, rather than waiting for the checker to fail it
+// gcPolicy.MinMarks times across the periodic interval.
+func (mgr *EndpointManager) NotifyStale(id uint16) {
+	mgr.gcMutex.Lock()
+	if mgr.gcFailures == nil {
+		mgr.gcFailures = make(map[uint16]int)
+	}
+	policy := mgr.gcPolicy.withDefaults()
+	// Seed the counter at MinMarks rather than clearing it, so that even
+	// callers using the non-immediate policy get id swept on the very next
+	// periodic tick instead of having to accumulate MinMarks consecutive
+	// failures from scratch.
+	mgr.gcFailures[id] = policy.MinMarks
+	immediate := policy.ImmediateSweepOnEvent
+	mgr.gcMutex.Unlock()
+
+	metrics.EndpointGCMarked.Inc()
+
+	if !immediate || mgr.checker == nil {
+		return
+	}
+	mgr.sweepEndpoints([]uint16{id})
+}
+
 // sweepEndpoints iterates through the specified list of endpoints marked for
 // deletion and attempts to garbage-collect them if they still exist.
 func (mgr *EndpointManager) sweepEndpoints(markedEndpoints []uint16) {
@@ -152,15 +349,12 @@ func (mgr *EndpointManager) sweepEndpoints(markedEndpoints []uint16) {
 
 	// 'markedEndpoints' were marked during the previous mark round, so
 	// they may no longer be valid endpoints. Narrow the list to only the
-	// endpoints that remain. Then, release the lock so DeleteEndpoint()
-	// below can independently grab it.
-	mgr.mutex.RLock()
+	// endpoints that remain.
 	for _, id := range markedEndpoints {
-		if ep, ok := mgr.endpoints[id]; ok {
+		if ep := mgr.lookupCiliumID(id); ep != nil {
 			toSweep = append(toSweep, ep)
 		}
 	}
-	mgr.mutex.RUnlock()
 
 	for _, ep := range toSweep {
 		log.WithFields(logrus.Fields{
@@ -171,6 +365,73 @@ func (mgr *EndpointManager) sweepEndpoints(markedEndpoints []uint16) {
 		}).Warning("Stray endpoint found. You may be affected by upstream Kubernetes issue #86944.")
 		// Callee handles the errors which we ignore.
 		_ = mgr.checker.DeleteEndpoint(ep)
+		metrics.EndpointGCSwept.Inc()
+	}
+}
+
+// EndpointEventKind describes the kind of change an EndpointEvent represents.
+type EndpointEventKind int
+
+const (
+	// EndpointEventAdd is emitted the first time an endpoint is referenced
+	// by the manager.
+	EndpointEventAdd EndpointEventKind = iota
+	// EndpointEventUpdate is emitted whenever an already-referenced
+	// endpoint's mapping is refreshed.
+	EndpointEventUpdate
+	// EndpointEventDelete is emitted when an endpoint is removed from the
+	// manager.
+	EndpointEventDelete
+)
+
+// EndpointEvent is delivered on the channel returned by Subscribe whenever an
+// endpoint is added, updated, or removed from the manager.
+type EndpointEvent struct {
+	Kind     EndpointEventKind
+	Endpoint *endpoint.Endpoint
+}
+
+// subscriberQueueSize is the number of events buffered per subscriber before
+// events start being dropped.
+const subscriberQueueSize = 64
+
+// Subscribe returns a channel on which Add/Update/Remove events for managed
+// endpoints are delivered, so that callers such as Hubble, the CEP
+// synchronizer, or the endpoint GC no longer need to poll GetEndpoints().
+// The returned channel is closed once ctx is cancelled.
+//
+// A slow subscriber does not block the manager: if its buffer is full, the
+// event is dropped and a warning is logged.
+func (mgr *EndpointManager) Subscribe(ctx context.Context) <-chan EndpointEvent {
+	ch := make(chan EndpointEvent, subscriberQueueSize)
+
+	mgr.subscribersMutex.Lock()
+	mgr.subscribers[ch] = struct{}{}
+	mgr.subscribersMutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		mgr.subscribersMutex.Lock()
+		delete(mgr.subscribers, ch)
+		mgr.subscribersMutex.Unlock()
+		close(ch)
+	}()
+
+	return ch
+}
+
+// publish delivers ev to every active subscriber without blocking.
+func (mgr *EndpointManager) publish(ev EndpointEvent) {
+	mgr.subscribersMutex.Lock()
+	defer mgr.subscribersMutex.Unlock()
+
+	for ch := range mgr.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			log.WithField(logfields.EndpointID, ev.Endpoint.ID).
+				Warning("Subscriber is not keeping up with endpoint events, dropping event")
+		}
 	}
 }
 
@@ -192,39 +453,75 @@ func waitForProxyCompletions(proxyWaitGroup *completion.WaitGroup) error {
 	return nil
 }
 
+// regenerationConcurrency returns the maximum number of endpoints that may be
+// regenerated, or have their policy maps updated, concurrently. It defaults
+// to runtime.NumCPU(), overridable via option.Config so that operators of
+// very large or very small nodes can tune it.
+func regenerationConcurrency() int {
+	if n := option.Config.EndpointRegenerationMaxConcurrency; n > 0 {
+		return n
+	}
+	return runtime.NumCPU()
+}
+
+// forEachEndpointBounded calls fn exactly once for every endpoint in eps,
+// running at most regenerationConcurrency() calls to fn concurrently from
+// the pool; once ctx is cancelled, the inline calls described below may run
+// alongside the calls still in the pool. It
+// returns once fn has been called (and returned) for every endpoint. If ctx
+// is cancelled while an endpoint is still waiting for a pool slot, fn is
+// still called for it — inline, rather than via a newly spawned goroutine,
+// since the pool is no longer accepting new work — so that callers (e.g.
+// RegenerateAllEndpoints's progress/failure accounting and metrics) always
+// see every endpoint accounted for instead of it being silently dropped.
+func forEachEndpointBounded(ctx context.Context, eps []*endpoint.Endpoint, fn func(ep *endpoint.Endpoint)) {
+	sem := make(chan struct{}, regenerationConcurrency())
+	var wg sync.WaitGroup
+	wg.Add(len(eps))
+
+	for _, ep := range eps {
+		select {
+		case sem <- struct{}{}:
+			go func(ep *endpoint.Endpoint) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				fn(ep)
+			}(ep)
+		case <-ctx.Done():
+			fn(ep)
+			wg.Done()
+		}
+	}
+
+	wg.Wait()
+}
+
 // UpdatePolicyMaps returns a WaitGroup which is signaled upon once all endpoints
 // have had their PolicyMaps updated against the Endpoint's desired policy state.
 func (mgr *EndpointManager) UpdatePolicyMaps(ctx context.Context) *sync.WaitGroup {
-	var epWG sync.WaitGroup
 	var wg sync.WaitGroup
 
 	proxyWaitGroup := completion.NewWaitGroup(ctx)
 
 	eps := mgr.GetEndpoints()
-	epWG.Add(len(eps))
 	wg.Add(1)
 
 	// This is in a goroutine to allow the caller to proceed with other tasks before waiting for the ACKs to complete
 	go func() {
 		// Wait for all the eps to have applied policy map
-		// changes before waiting for the changes to be ACKed
-		epWG.Wait()
+		// changes, bounded by regenerationConcurrency(), before waiting for
+		// the changes to be ACKed.
+		forEachEndpointBounded(ctx, eps, func(ep *endpoint.Endpoint) {
+			if err := ep.ApplyPolicyMapChanges(proxyWaitGroup); err != nil {
+				ep.Logger("endpointmanager").WithError(err).Warning("Failed to apply policy map changes. These will be re-applied in future updates.")
+			}
+		})
 		if err := waitForProxyCompletions(proxyWaitGroup); err != nil {
 			log.WithError(err).Warning("Failed to apply L7 proxy policy changes. These will be re-applied in future updates.")
 		}
 		wg.Done()
 	}()
 
-	// TODO: bound by number of CPUs?
-	for _, ep := range eps {
-		go func(ep *endpoint.Endpoint) {
-			if err := ep.ApplyPolicyMapChanges(proxyWaitGroup); err != nil {
-				ep.Logger("endpointmanager").WithError(err).Warning("Failed to apply policy map changes. These will be re-applied in future updates.")
-			}
-			epWG.Done()
-		}(ep)
-	}
-
 	return &wg
 }
 
@@ -246,6 +543,34 @@ func (mgr *EndpointManager) InitMetrics() {
 			func() float64 { return float64(len(mgr.GetEndpoints())) },
 		)
 		metrics.MustRegister(metrics.EndpointCount)
+
+		metrics.EndpointRegenerationFailed = prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Name:      "endpoint_regeneration_failed_total",
+			Help:      "Number of endpoint regenerations that failed after exhausting retries",
+		})
+		metrics.MustRegister(metrics.EndpointRegenerationFailed)
+
+		metrics.EndpointGCMarked = prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Name:      "endpoint_gc_marked_total",
+			Help:      "Number of times an endpoint failed a GC health check and had its failure counter incremented",
+		})
+		metrics.MustRegister(metrics.EndpointGCMarked)
+
+		metrics.EndpointGCUnmarked = prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Name:      "endpoint_gc_unmarked_total",
+			Help:      "Number of times an endpoint recovered and had its GC failure counter cleared or decremented",
+		})
+		metrics.MustRegister(metrics.EndpointGCUnmarked)
+
+		metrics.EndpointGCSwept = prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Name:      "endpoint_gc_swept_total",
+			Help:      "Number of endpoints removed by the GC after exceeding the configured failure hysteresis",
+		})
+		metrics.MustRegister(metrics.EndpointGCSwept)
 	})
 }
 
@@ -272,16 +597,20 @@ func (mgr *EndpointManager) AllocateID(currID uint16) (uint16, error) {
 
 // RemoveID removes the id from the endpoints map in the EndpointManager.
 func (mgr *EndpointManager) RemoveID(currID uint16) {
-	mgr.mutex.Lock()
-	defer mgr.mutex.Unlock()
-	delete(mgr.endpoints, currID)
+	shard := mgr.idShardFor(currID)
+	shard.mutex.Lock()
+	ep, ok := shard.byID[currID]
+	delete(shard.byID, currID)
+	shard.mutex.Unlock()
+
+	atomic.AddUint64(&mgr.snapshotVersion, 1)
+	if ok {
+		mgr.publish(EndpointEvent{Kind: EndpointEventDelete, Endpoint: ep})
+	}
 }
 
 // Lookup looks up the endpoint by prefix id
 func (mgr *EndpointManager) Lookup(id string) (*endpoint.Endpoint, error) {
-	mgr.mutex.RLock()
-	defer mgr.mutex.RUnlock()
-
 	prefix, eid, err := endpointid.Parse(id)
 	if err != nil {
 		return nil, err
@@ -323,55 +652,36 @@ func (mgr *EndpointManager) Lookup(id string) (*endpoint.Endpoint, error) {
 
 // LookupCiliumID looks up endpoint by endpoint ID
 func (mgr *EndpointManager) LookupCiliumID(id uint16) *endpoint.Endpoint {
-	mgr.mutex.RLock()
-	ep := mgr.lookupCiliumID(id)
-	mgr.mutex.RUnlock()
-	return ep
+	return mgr.lookupCiliumID(id)
 }
 
 // LookupContainerID looks up endpoint by Docker ID
 func (mgr *EndpointManager) LookupContainerID(id string) *endpoint.Endpoint {
-	mgr.mutex.RLock()
-	ep := mgr.lookupContainerID(id)
-	mgr.mutex.RUnlock()
-	return ep
+	return mgr.lookupContainerID(id)
 }
 
 // LookupIPv4 looks up endpoint by IPv4 address
 func (mgr *EndpointManager) LookupIPv4(ipv4 string) *endpoint.Endpoint {
-	mgr.mutex.RLock()
-	ep := mgr.lookupIPv4(ipv4)
-	mgr.mutex.RUnlock()
-	return ep
+	return mgr.lookupIPv4(ipv4)
 }
 
 // LookupIPv6 looks up endpoint by IPv6 address
 func (mgr *EndpointManager) LookupIPv6(ipv6 string) *endpoint.Endpoint {
-	mgr.mutex.RLock()
-	ep := mgr.lookupIPv6(ipv6)
-	mgr.mutex.RUnlock()
-	return ep
+	return mgr.lookupIPv6(ipv6)
 }
 
 // LookupIP looks up endpoint by IP address
 func (mgr *EndpointManager) LookupIP(ip net.IP) (ep *endpoint.Endpoint) {
 	addr := ip.String()
-	mgr.mutex.RLock()
 	if ip.To4() != nil {
-		ep = mgr.lookupIPv4(addr)
-	} else {
-		ep = mgr.lookupIPv6(addr)
+		return mgr.lookupIPv4(addr)
 	}
-	mgr.mutex.RUnlock()
-	return ep
+	return mgr.lookupIPv6(addr)
 }
 
 // LookupPodName looks up endpoint by namespace + pod name
 func (mgr *EndpointManager) LookupPodName(name string) *endpoint.Endpoint {
-	mgr.mutex.RLock()
-	ep := mgr.lookupPodNameLocked(name)
-	mgr.mutex.RUnlock()
-	return ep
+	return mgr.lookupPodNameLocked(name)
 }
 
 // ReleaseID releases the ID of the specified endpoint from the EndpointManager.
@@ -389,119 +699,276 @@ func (mgr *EndpointManager) WaitEndpointRemoved(ep *endpoint.Endpoint) {
 
 // RemoveAll removes all endpoints from the global maps.
 func (mgr *EndpointManager) RemoveAll() {
-	mgr.mutex.Lock()
-	defer mgr.mutex.Unlock()
 	endpointid.ReallocatePool()
-	mgr.endpoints = map[uint16]*endpoint.Endpoint{}
-	mgr.endpointsAux = map[string]*endpoint.Endpoint{}
+	// Bump snapshotVersion once per shard cleared, the same as every other
+	// writer does for its own single shard, rather than once at the end.
+	// scanShards only trusts a scan if snapshotVersion is unchanged across
+	// the whole walk; bumping once per shard guarantees that any scan
+	// overlapping any part of this clear observes at least one bump and
+	// retries, instead of racing to a torn mix of cleared/uncleared shards.
+	for _, shard := range mgr.idShards {
+		shard.mutex.Lock()
+		shard.byID = map[uint16]*endpoint.Endpoint{}
+		shard.mutex.Unlock()
+		atomic.AddUint64(&mgr.snapshotVersion, 1)
+	}
+	for _, shard := range mgr.auxShards {
+		shard.mutex.Lock()
+		shard.byKey = map[string]*endpoint.Endpoint{}
+		shard.mutex.Unlock()
+	}
+}
+
+// maxSnapshotScanAttempts bounds how many times scanShards retries a scan
+// that raced with a concurrent write, before it gives up and accepts the
+// result as a best-effort snapshot.
+const maxSnapshotScanAttempts = 8
+
+// scanShards walks every idShard and returns the endpoints it finds together
+// with the snapshotVersion observed once the walk is done. If
+// snapshotVersion changed while scanning (i.e. a write landed concurrently),
+// the scan is retried, since the result cannot be trusted to include that
+// write: every writer mutates its shard and only then bumps snapshotVersion,
+// so an unchanged version before and after the walk guarantees the walk
+// observed a consistent, fully up-to-date set of shards.
+func (mgr *EndpointManager) scanShards() (uint64, []*endpoint.Endpoint) {
+	var eps []*endpoint.Endpoint
+	for attempt := 0; ; attempt++ {
+		before := atomic.LoadUint64(&mgr.snapshotVersion)
+
+		eps = make([]*endpoint.Endpoint, 0, numShards*8)
+		for _, shard := range mgr.idShards {
+			shard.mutex.RLock()
+			for _, ep := range shard.byID {
+				eps = append(eps, ep)
+			}
+			shard.mutex.RUnlock()
+		}
+
+		after := atomic.LoadUint64(&mgr.snapshotVersion)
+		if before == after || attempt >= maxSnapshotScanAttempts {
+			return after, eps
+		}
+	}
 }
 
 // lookupCiliumID looks up endpoint by endpoint ID
 func (mgr *EndpointManager) lookupCiliumID(id uint16) *endpoint.Endpoint {
-	if ep, ok := mgr.endpoints[id]; ok {
-		return ep
-	}
-	return nil
+	shard := mgr.idShardFor(id)
+	shard.mutex.RLock()
+	ep := shard.byID[id]
+	shard.mutex.RUnlock()
+	return ep
+}
+
+func (mgr *EndpointManager) lookupAux(prefix endpointid.PrefixType, key string) *endpoint.Endpoint {
+	id := endpointid.NewID(prefix, key)
+	shard := mgr.auxShardFor(id)
+	shard.mutex.RLock()
+	ep := shard.byKey[id]
+	shard.mutex.RUnlock()
+	return ep
 }
 
 func (mgr *EndpointManager) lookupDockerEndpoint(id string) *endpoint.Endpoint {
-	if ep, ok := mgr.endpointsAux[endpointid.NewID(endpointid.DockerEndpointPrefix, id)]; ok {
-		return ep
-	}
-	return nil
+	return mgr.lookupAux(endpointid.DockerEndpointPrefix, id)
 }
 
 func (mgr *EndpointManager) lookupPodNameLocked(name string) *endpoint.Endpoint {
-	if ep, ok := mgr.endpointsAux[endpointid.NewID(endpointid.PodNamePrefix, name)]; ok {
-		return ep
-	}
-	return nil
+	return mgr.lookupAux(endpointid.PodNamePrefix, name)
 }
 
 func (mgr *EndpointManager) lookupDockerContainerName(name string) *endpoint.Endpoint {
-	if ep, ok := mgr.endpointsAux[endpointid.NewID(endpointid.ContainerNamePrefix, name)]; ok {
-		return ep
-	}
-	return nil
+	return mgr.lookupAux(endpointid.ContainerNamePrefix, name)
 }
 
 func (mgr *EndpointManager) lookupIPv4(ipv4 string) *endpoint.Endpoint {
-	if ep, ok := mgr.endpointsAux[endpointid.NewID(endpointid.IPv4Prefix, ipv4)]; ok {
-		return ep
-	}
-	return nil
+	return mgr.lookupAux(endpointid.IPv4Prefix, ipv4)
 }
 
 func (mgr *EndpointManager) lookupIPv6(ipv6 string) *endpoint.Endpoint {
-	if ep, ok := mgr.endpointsAux[endpointid.NewID(endpointid.IPv6Prefix, ipv6)]; ok {
-		return ep
-	}
-	return nil
+	return mgr.lookupAux(endpointid.IPv6Prefix, ipv6)
 }
 
 func (mgr *EndpointManager) lookupContainerID(id string) *endpoint.Endpoint {
-	if ep, ok := mgr.endpointsAux[endpointid.NewID(endpointid.ContainerIdPrefix, id)]; ok {
-		return ep
-	}
-	return nil
+	return mgr.lookupAux(endpointid.ContainerIdPrefix, id)
 }
 
 // UpdateIDReference updates the endpoints map in the EndpointManager for
 // the given Endpoint.
 func (mgr *EndpointManager) UpdateIDReference(ep *endpoint.Endpoint) {
-	mgr.mutex.Lock()
-	defer mgr.mutex.Unlock()
 	if ep == nil {
 		return
 	}
-	mgr.endpoints[ep.ID] = ep
+
+	shard := mgr.idShardFor(ep.ID)
+	shard.mutex.Lock()
+	_, exists := shard.byID[ep.ID]
+	shard.byID[ep.ID] = ep
+	shard.mutex.Unlock()
+
+	atomic.AddUint64(&mgr.snapshotVersion, 1)
+
+	kind := EndpointEventUpdate
+	if !exists {
+		kind = EndpointEventAdd
+	}
+	mgr.publish(EndpointEvent{Kind: kind, Endpoint: ep})
 }
 
 // UpdateReferences updates maps the contents of mappings to the specified
 // endpoint.
 func (mgr *EndpointManager) UpdateReferences(mappings map[endpointid.PrefixType]string, ep *endpoint.Endpoint) {
-	mgr.mutex.Lock()
-	defer mgr.mutex.Unlock()
 	for k := range mappings {
 		id := endpointid.NewID(k, mappings[k])
-		mgr.endpointsAux[id] = ep
-
+		shard := mgr.auxShardFor(id)
+		shard.mutex.Lock()
+		shard.byKey[id] = ep
+		shard.mutex.Unlock()
 	}
+
+	mgr.publish(EndpointEvent{Kind: EndpointEventUpdate, Endpoint: ep})
 }
 
 // RemoveReferences removes the mappings from the endpointmanager.
 func (mgr *EndpointManager) RemoveReferences(mappings map[endpointid.PrefixType]string) {
-	mgr.mutex.Lock()
-	defer mgr.mutex.Unlock()
 	for prefix := range mappings {
 		id := endpointid.NewID(prefix, mappings[prefix])
-		delete(mgr.endpointsAux, id)
+		shard := mgr.auxShardFor(id)
+		shard.mutex.Lock()
+		delete(shard.byKey, id)
+		shard.mutex.Unlock()
 	}
 }
 
-// RegenerateAllEndpoints calls a setState for each endpoint and
-// regenerates if state transaction is valid. During this process, the endpoint
-// list is locked and cannot be modified.
-// Returns a waiting group that can be used to know when all the endpoints are
-// regenerated.
-func (mgr *EndpointManager) RegenerateAllEndpoints(regenMetadata *regeneration.ExternalRegenerationMetadata) *sync.WaitGroup {
-	var wg sync.WaitGroup
+const (
+	// maxRegenerationRetries bounds how many times a single endpoint's
+	// regeneration is retried after being aborted by a cancelled context
+	// before it is given up on for this RegenerateAllEndpoints call.
+	maxRegenerationRetries = 3
+	// regenerationRetryBaseDelay is the initial delay before retrying an
+	// aborted regeneration; it is doubled on every subsequent retry.
+	regenerationRetryBaseDelay = 100 * time.Millisecond
+)
+
+// RegenerationHandle reports on the progress of a RegenerateAllEndpoints
+// call. It is safe for concurrent use.
+type RegenerationHandle struct {
+	total     int32
+	completed int32
+	failed    int32
+	done      chan struct{}
+}
 
+// Total returns the number of endpoints that were queued for regeneration.
+func (h *RegenerationHandle) Total() int { return int(atomic.LoadInt32(&h.total)) }
+
+// Completed returns the number of endpoints that have finished regenerating
+// successfully. Endpoints that failed are counted by Failed instead.
+func (h *RegenerationHandle) Completed() int { return int(atomic.LoadInt32(&h.completed)) }
+
+// Failed returns the number of endpoints whose regeneration was aborted and
+// could not be completed even after retrying.
+func (h *RegenerationHandle) Failed() int { return int(atomic.LoadInt32(&h.failed)) }
+
+// Wait blocks until every queued endpoint has finished regenerating, or
+// until ctx is cancelled, whichever happens first.
+func (h *RegenerationHandle) Wait(ctx context.Context) error {
+	select {
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RegenerateAllEndpoints calls a setState for each endpoint and regenerates
+// if the state transition is valid. Regenerations run on a worker pool
+// bounded by regenerationConcurrency() rather than one goroutine per
+// endpoint, so that a large node cannot flood the runtime or the L7 proxy
+// ACK path. If ctx is cancelled (e.g. the manager is shutting down), any
+// regenerations still queued are retried a bounded number of times with
+// exponential backoff before being marked failed.
+// Returns a RegenerationHandle that callers can use to observe progress
+// instead of blocking on an opaque sync.WaitGroup.
+func (mgr *EndpointManager) RegenerateAllEndpoints(ctx context.Context, regenMetadata *regeneration.ExternalRegenerationMetadata) *RegenerationHandle {
 	eps := mgr.GetEndpoints()
-	wg.Add(len(eps))
+
+	handle := &RegenerationHandle{
+		total: int32(len(eps)),
+		done:  make(chan struct{}),
+	}
 
 	// Dereference "reason" field outside of logging statement; see
 	// https://github.com/sirupsen/logrus/issues/1003.
 	reason := regenMetadata.Reason
-	log.WithFields(logrus.Fields{"reason": reason}).Info("regenerating all endpoints")
-	for _, ep := range eps {
-		go func(ep *endpoint.Endpoint) {
-			<-ep.RegenerateIfAlive(regenMetadata)
-			wg.Done()
-		}(ep)
+	log.WithFields(logrus.Fields{"reason": reason, "concurrency": regenerationConcurrency()}).Info("regenerating all endpoints")
+
+	go func() {
+		defer close(handle.done)
+
+		forEachEndpointBounded(ctx, eps, func(ep *endpoint.Endpoint) {
+			if mgr.regenerateWithRetry(ctx, ep, regenMetadata) {
+				atomic.AddInt32(&handle.completed, 1)
+			} else {
+				atomic.AddInt32(&handle.failed, 1)
+				metrics.EndpointRegenerationFailed.Inc()
+			}
+		})
+	}()
+
+	return handle
+}
+
+// regenerateWithRetry regenerates ep, retrying with exponential backoff if
+// the attempt is aborted by ctx being cancelled mid-flight. It returns false
+// once maxRegenerationRetries has been exceeded without a successful
+// regeneration.
+func (mgr *EndpointManager) regenerateWithRetry(ctx context.Context, ep *endpoint.Endpoint, regenMetadata *regeneration.ExternalRegenerationMetadata) bool {
+	ok := retryWithBackoff(ctx, maxRegenerationRetries, regenerationRetryBaseDelay, func() <-chan struct{} {
+		return ep.RegenerateIfAlive(regenMetadata)
+	})
+	if !ok {
+		ep.Logger("endpointmanager").Warning("Giving up on endpoint regeneration after repeated cancellation")
 	}
+	return ok
+}
 
-	return &wg
+// retryWithBackoff calls attempt and waits for either its returned channel to
+// fire (success) or ctx to be cancelled (abort). On abort it retries, up to
+// maxRetries times, sleeping baseDelay before the first retry and doubling
+// the delay on every subsequent one. It is the retry/backoff core of
+// regenerateWithRetry, split out so it can be unit tested without a real
+// *endpoint.Endpoint.
+func retryWithBackoff(ctx context.Context, maxRetries int, baseDelay time.Duration, attempt func() <-chan struct{}) bool {
+	delay := baseDelay
+	for try := 0; try <= maxRetries; try++ {
+		done := attempt()
+		select {
+		case <-done:
+			return true
+		case <-ctx.Done():
+			// An attempt that has already completed counts as a success
+			// even though ctx is cancelled too.
+			select {
+			case <-done:
+				return true
+			default:
+			}
+		}
+
+		if try == maxRetries {
+			break
+		}
+		// ctx is already cancelled at this point (that's how this branch
+		// was reached), so selecting on ctx.Done() again would resolve
+		// instantly and skip the backoff entirely. The bounded retry count
+		// above, not ctx, is what stops this loop from retrying forever.
+		time.Sleep(delay)
+		delay *= 2
+	}
+
+	return false
 }
 
 // HasGlobalCT returns true if the endpoints have a global CT, false otherwise.
@@ -516,26 +983,39 @@ func (mgr *EndpointManager) HasGlobalCT() bool {
 }
 
 // GetEndpoints returns a slice of all endpoints present in endpoint manager.
+// The returned slice is read from a cached snapshot that is rebuilt lazily,
+// only when a write has actually happened since it was last built, so that
+// the hot Update*/Remove* write path never pays the cost of scanning every
+// shard.
 func (mgr *EndpointManager) GetEndpoints() []*endpoint.Endpoint {
-	mgr.mutex.RLock()
-	eps := make([]*endpoint.Endpoint, 0, len(mgr.endpoints))
-	for _, ep := range mgr.endpoints {
-		eps = append(eps, ep)
+	target := atomic.LoadUint64(&mgr.snapshotVersion)
+	if cached := mgr.snapshot.Load().(*endpointSnapshot); cached.version == target {
+		return cached.eps
+	}
+
+	mgr.snapshotMutex.Lock()
+	defer mgr.snapshotMutex.Unlock()
+
+	// Another goroutine may have rebuilt the snapshot while we were
+	// waiting for snapshotMutex.
+	if cached := mgr.snapshot.Load().(*endpointSnapshot); cached.version == atomic.LoadUint64(&mgr.snapshotVersion) {
+		return cached.eps
 	}
-	mgr.mutex.RUnlock()
+
+	version, eps := mgr.scanShards()
+	mgr.snapshot.Store(&endpointSnapshot{version: version, eps: eps})
 	return eps
 }
 
 // GetPolicyEndpoints returns a map of all endpoints present in endpoint
 // manager as policy.Endpoint interface set for the map key.
 func (mgr *EndpointManager) GetPolicyEndpoints() map[policy.Endpoint]struct{} {
-	mgr.mutex.RLock()
-	eps := make(map[policy.Endpoint]struct{}, len(mgr.endpoints))
-	for _, ep := range mgr.endpoints {
-		eps[ep] = struct{}{}
+	eps := mgr.GetEndpoints()
+	policyEps := make(map[policy.Endpoint]struct{}, len(eps))
+	for _, ep := range eps {
+		policyEps[ep] = struct{}{}
 	}
-	mgr.mutex.RUnlock()
-	return eps
+	return policyEps
 }
 
 // AddEndpoint takes the prepared endpoint object and starts managing it.
@@ -0,0 +1,125 @@
+// Copyright 2016-2019 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpointmanager
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cilium/cilium/pkg/endpoint"
+	endpointid "github.com/cilium/cilium/pkg/endpoint/id"
+)
+
+const benchSeedSize = 4096
+
+func ipv4For(id uint16) string {
+	return fmt.Sprintf("10.%d.%d.%d", id>>8, id&0xff, 1)
+}
+
+func seedBenchmarkManager(b *testing.B) *EndpointManager {
+	mgr := NewEndpointManager(nil)
+	for i := 0; i < benchSeedSize; i++ {
+		id := uint16(i)
+		ep := &endpoint.Endpoint{ID: id}
+		mgr.UpdateIDReference(ep)
+		mgr.UpdateReferences(map[endpointid.PrefixType]string{
+			endpointid.IPv4Prefix: ipv4For(id),
+		}, ep)
+	}
+	return mgr
+}
+
+// BenchmarkConcurrentLookupInsertRemove exercises LookupIPv4 running
+// concurrently with UpdateIDReference/RemoveID, to show that sharding keeps
+// lookups from contending with the insert/remove path on the same
+// EndpointManager.
+func BenchmarkConcurrentLookupInsertRemove(b *testing.B) {
+	mgr := seedBenchmarkManager(b)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var i uint16
+		for pb.Next() {
+			id := i % benchSeedSize
+			if i%4 == 3 {
+				ep := &endpoint.Endpoint{ID: id}
+				mgr.UpdateIDReference(ep)
+				mgr.RemoveID(id)
+			} else {
+				mgr.LookupIPv4(ipv4For(id))
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkPopulate measures populating an EndpointManager from scratch with
+// benchSeedSize endpoints, i.e. the write-only path with no readers
+// involved. This should scale linearly in N: UpdateIDReference only touches
+// its own shard, and GetEndpoints is never called during this benchmark, so
+// nothing here should trigger a snapshot rebuild.
+func BenchmarkPopulate(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = seedBenchmarkManager(b)
+	}
+}
+
+// BenchmarkConcurrentInsertRemove exercises UpdateIDReference/RemoveID with
+// no concurrent readers, to isolate the cost of the write path itself (shard
+// lock + version bump) from any snapshot rebuild work.
+func BenchmarkConcurrentInsertRemove(b *testing.B) {
+	mgr := seedBenchmarkManager(b)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var i uint16
+		for pb.Next() {
+			id := i % benchSeedSize
+			ep := &endpoint.Endpoint{ID: id}
+			mgr.UpdateIDReference(ep)
+			mgr.RemoveID(id)
+			i++
+		}
+	})
+}
+
+// BenchmarkGetEndpoints measures the cost of fetching the full endpoint
+// list while concurrent UpdateIDReference calls are in flight. Every such
+// call invalidates the cached snapshot, so most GetEndpoints calls here
+// rebuild it under snapshotMutex by scanning every shard.
+func BenchmarkGetEndpoints(b *testing.B) {
+	mgr := seedBenchmarkManager(b)
+
+	stop := make(chan struct{})
+	go func() {
+		var id uint16
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				ep := &endpoint.Endpoint{ID: id % benchSeedSize}
+				mgr.UpdateIDReference(ep)
+				id++
+			}
+		}
+	}()
+	defer close(stop)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = mgr.GetEndpoints()
+	}
+}
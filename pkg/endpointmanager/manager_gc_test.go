@@ -0,0 +1,119 @@
+// Copyright 2016-2019 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpointmanager
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cilium/cilium/pkg/endpoint"
+)
+
+// failingChecker fails Check for every endpoint whose ID is in failIDs.
+type failingChecker struct {
+	failIDs map[uint16]struct{}
+	deleted []uint16
+}
+
+func (c *failingChecker) Check(ep *endpoint.Endpoint) error {
+	if _, fail := c.failIDs[ep.ID]; fail {
+		return errors.New("endpoint is unhealthy")
+	}
+	return nil
+}
+
+func (c *failingChecker) DeleteEndpoint(ep *endpoint.Endpoint) int {
+	c.deleted = append(c.deleted, ep.ID)
+	return 1
+}
+
+func TestMarkEndpointsRequiresMinMarksBeforeSweep(t *testing.T) {
+	mgr := NewEndpointManager(nil)
+	mgr.gcPolicy = GCPolicy{MinMarks: 2}.withDefaults()
+	mgr.gcFailures = make(map[uint16]int)
+	checker := &failingChecker{failIDs: map[uint16]struct{}{1: {}}}
+	mgr.checker = checker
+
+	eps := []*endpoint.Endpoint{{ID: 1}}
+
+	if toSweep := mgr.markEndpoints(eps); len(toSweep) != 0 {
+		t.Fatalf("expected no sweep after 1 failure, got %v", toSweep)
+	}
+	if got := mgr.gcFailures[1]; got != 1 {
+		t.Fatalf("expected failure count 1, got %d", got)
+	}
+
+	toSweep := mgr.markEndpoints(eps)
+	if len(toSweep) != 1 || toSweep[0] != 1 {
+		t.Fatalf("expected endpoint 1 to be due for sweep after 2 failures, got %v", toSweep)
+	}
+	if _, marked := mgr.gcFailures[1]; marked {
+		t.Fatal("expected failure counter to be cleared once queued for sweep")
+	}
+}
+
+func TestMarkEndpointsDecrementsOnSuccessWhenNotConsecutive(t *testing.T) {
+	mgr := NewEndpointManager(nil)
+	mgr.gcPolicy = GCPolicy{MinMarks: 3, RequireConsecutive: false}.withDefaults()
+	mgr.gcFailures = map[uint16]int{1: 2}
+	mgr.checker = &failingChecker{}
+
+	eps := []*endpoint.Endpoint{{ID: 1}}
+	mgr.markEndpoints(eps)
+
+	if got := mgr.gcFailures[1]; got != 1 {
+		t.Fatalf("expected failure count to decrement to 1, got %d", got)
+	}
+}
+
+func TestNotifyStaleSeedsCounterForNextSweep(t *testing.T) {
+	mgr := NewEndpointManager(nil)
+	mgr.gcPolicy = GCPolicy{MinMarks: 2, ImmediateSweepOnEvent: false}.withDefaults()
+	mgr.gcFailures = make(map[uint16]int)
+	checker := &failingChecker{}
+	mgr.checker = checker
+
+	mgr.NotifyStale(7)
+
+	if got := mgr.gcFailures[7]; got != mgr.gcPolicy.MinMarks {
+		t.Fatalf("expected NotifyStale to seed the counter at MinMarks (%d), got %d", mgr.gcPolicy.MinMarks, got)
+	}
+
+	// The next periodic mark round must sweep endpoint 7 immediately, since
+	// a healthy Check() call should not need to undo what NotifyStale did.
+	mgr.UpdateIDReference(&endpoint.Endpoint{ID: 7})
+	toSweep := mgr.markEndpoints(mgr.GetEndpoints())
+	if len(toSweep) != 1 || toSweep[0] != 7 {
+		t.Fatalf("expected endpoint 7 to be swept on the next tick, got %v", toSweep)
+	}
+	if len(checker.deleted) != 0 {
+		t.Fatal("markEndpoints should not itself delete endpoints")
+	}
+}
+
+func TestNotifyStaleImmediateSweepsRightAway(t *testing.T) {
+	mgr := NewEndpointManager(nil)
+	mgr.gcPolicy = GCPolicy{MinMarks: 2, ImmediateSweepOnEvent: true}.withDefaults()
+	mgr.gcFailures = make(map[uint16]int)
+	checker := &failingChecker{}
+	mgr.checker = checker
+
+	mgr.UpdateIDReference(&endpoint.Endpoint{ID: 9})
+	mgr.NotifyStale(9)
+
+	if len(checker.deleted) != 1 || checker.deleted[0] != 9 {
+		t.Fatalf("expected endpoint 9 to be swept immediately, deleted=%v", checker.deleted)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2016-2019 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpointmanager
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/pkg/endpoint"
+)
+
+// closedChan is a channel that is already closed, i.e. an attempt that
+// always succeeds immediately.
+func closedChan() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+
+func TestRetryWithBackoffSucceedsWithoutRetry(t *testing.T) {
+	calls := 0
+	ok := retryWithBackoff(context.Background(), 3, time.Millisecond, func() <-chan struct{} {
+		calls++
+		return closedChan()
+	})
+	if !ok {
+		t.Fatal("expected success")
+	}
+	if calls != 1 {
+		t.Fatalf("expected exactly 1 attempt, got %d", calls)
+	}
+}
+
+func TestRetryWithBackoffRetriesUntilSuccess(t *testing.T) {
+	var calls int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	ok := retryWithBackoff(ctx, 3, 5*time.Millisecond, func() <-chan struct{} {
+		n := atomic.AddInt32(&calls, 1)
+		if n < 3 {
+			// Simulate an attempt that never completes on its own; only
+			// ctx cancellation aborts it.
+			cancel()
+			never := make(chan struct{})
+			return never
+		}
+		return closedChan()
+	})
+	if !ok {
+		t.Fatal("expected eventual success")
+	}
+	if calls != 3 {
+		t.Fatalf("expected exactly 3 attempts, got %d", calls)
+	}
+	// Two retries, backing off 5ms then 10ms, must have actually elapsed
+	// real wall-clock time rather than returning immediately: this is the
+	// behavior the dead-backoff bug broke.
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Fatalf("expected backoff delays to have elapsed, only took %s", elapsed)
+	}
+}
+
+func TestRetryWithBackoffGivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ok := retryWithBackoff(ctx, 2, time.Millisecond, func() <-chan struct{} {
+		atomic.AddInt32(&calls, 1)
+		never := make(chan struct{})
+		return never
+	})
+	if ok {
+		t.Fatal("expected failure after exhausting retries")
+	}
+	if calls != 3 {
+		t.Fatalf("expected maxRetries+1 = 3 attempts, got %d", calls)
+	}
+}
+
+// TestForEachEndpointBoundedCallsFnForCancelledEndpoints verifies that
+// endpoints which never acquire a semaphore slot because ctx is already
+// cancelled are still routed through fn, instead of being silently dropped
+// from the caller's completed/failed accounting.
+func TestForEachEndpointBoundedCallsFnForCancelledEndpoints(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	eps := make([]*endpoint.Endpoint, 8)
+	for i := range eps {
+		eps[i] = &endpoint.Endpoint{ID: uint16(i)}
+	}
+
+	var called int32
+	forEachEndpointBounded(ctx, eps, func(ep *endpoint.Endpoint) {
+		atomic.AddInt32(&called, 1)
+	})
+
+	if int(called) != len(eps) {
+		t.Fatalf("expected fn to be called for all %d endpoints, got %d", len(eps), called)
+	}
+}
+
+// TestForEachEndpointBoundedRunsEveryEndpoint covers the uncancelled path,
+// making sure every endpoint is still processed exactly once.
+func TestForEachEndpointBoundedRunsEveryEndpoint(t *testing.T) {
+	eps := make([]*endpoint.Endpoint, 32)
+	for i := range eps {
+		eps[i] = &endpoint.Endpoint{ID: uint16(i)}
+	}
+
+	seen := make([]int32, len(eps))
+	forEachEndpointBounded(context.Background(), eps, func(ep *endpoint.Endpoint) {
+		atomic.AddInt32(&seen[ep.ID], 1)
+	})
+
+	for id, n := range seen {
+		if n != 1 {
+			t.Fatalf("endpoint %d: expected fn called exactly once, got %d", id, n)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2016-2019 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package option
+
+// ConntrackLocal is the name of the per-endpoint option which restricts
+// connection tracking to the local endpoint rather than sharing a global
+// table.
+const ConntrackLocal = "ConntrackLocal"
+
+// DaemonConfig is the configuration of the Cilium daemon, populated from
+// CLI flags and the configuration file.
+type DaemonConfig struct {
+	// DryMode, when true, disables all side effects that require a running
+	// datapath (BPF maps, netlink, etc), so that the daemon can be
+	// exercised in unit tests and `--dry-mode` invocations.
+	DryMode bool
+
+	// EndpointRegenerationMaxConcurrency bounds how many endpoints may be
+	// regenerated, or have their policy maps updated, concurrently by the
+	// EndpointManager. A value <= 0 means the manager falls back to
+	// runtime.NumCPU(). It is read from Config only; no command-line flag
+	// sets it.
+	EndpointRegenerationMaxConcurrency int
+}
+
+// Config is the global instance of the daemon configuration.
+var Config = &DaemonConfig{}